@@ -1,14 +1,18 @@
 package cache
 
 import (
+	"container/list"
 	"fmt"
+	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type Item[T any] struct {
 	Object     T
 	Expiration int64
+	Accessed   int64
 }
 
 func (item Item[T]) Expired() bool {
@@ -27,30 +31,39 @@ type Cache[T any] struct {
 	*cache[T]
 }
 
+// entry is the value stored in a cache's linked list; it lets the list
+// element be mapped back to the key it belongs to so the tail (the
+// least-recently-accessed entry) can be evicted in O(1).
+type entry[T any] struct {
+	key  string
+	item Item[T]
+}
+
 type cache[T any] struct {
 	defaultExpiration time.Duration
-	items             map[string]Item[T]
+	maxItems          int
+	items             map[string]*list.Element
+	ll                *list.List
 	mu                sync.RWMutex
 	onEvicted         func(string, any)
+	janitor           *janitor[T]
+	inflightMu        sync.Mutex
+	inflight          map[string]*inflight[T]
 }
 
 func (c *cache[T]) Set(k string, x T, d time.Duration) {
-	var e int64
-	if d == DefaultExpiration {
-		d = c.defaultExpiration
-	}
-	if d > 0 {
-		e = time.Now().Add(d).UnixNano()
-	}
 	c.mu.Lock()
-	c.items[k] = Item[T]{
-		Object:     x,
-		Expiration: e,
-	}
+	ek, ev, evicted := c.set(k, x, d)
 	c.mu.Unlock()
+	if evicted {
+		c.onEvicted(ek, ev)
+	}
 }
 
-func (c *cache[T]) set(k string, x T, d time.Duration) {
+// set stores k without locking, returning the key and value of an entry
+// evicted to enforce maxItems, if any. Callers must hold c.mu and fire
+// onEvicted themselves once it is safe to do so.
+func (c *cache[T]) set(k string, x T, d time.Duration) (evictedKey string, evictedValue any, evicted bool) {
 	var e int64
 	if d == DefaultExpiration {
 		d = c.defaultExpiration
@@ -58,10 +71,28 @@ func (c *cache[T]) set(k string, x T, d time.Duration) {
 	if d > 0 {
 		e = time.Now().Add(d).UnixNano()
 	}
-	c.items[k] = Item[T]{
-		Object:     x,
-		Expiration: e,
+	item := Item[T]{Object: x, Expiration: e, Accessed: time.Now().UnixNano()}
+
+	if el, found := c.items[k]; found {
+		el.Value.(*entry[T]).item = item
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	el := c.ll.PushFront(&entry[T]{key: k, item: item})
+	c.items[k] = el
+
+	if c.maxItems > 0 && len(c.items) > c.maxItems {
+		if back := c.ll.Back(); back != nil {
+			en := back.Value.(*entry[T])
+			delete(c.items, en.key)
+			c.ll.Remove(back)
+			if c.onEvicted != nil {
+				return en.key, en.item.Object, true
+			}
+		}
 	}
+	return
 }
 
 func (c *cache[T]) SetDefault(k string, x T) {
@@ -75,8 +106,11 @@ func (c *cache[T]) Add(k string, x T, d time.Duration) error {
 		c.mu.Unlock()
 		return fmt.Errorf("Item %s already exists", k)
 	}
-	c.set(k, x, d)
+	ek, ev, evicted := c.set(k, x, d)
 	c.mu.Unlock()
+	if evicted {
+		c.onEvicted(ek, ev)
+	}
 	return nil
 }
 
@@ -87,64 +121,110 @@ func (c *cache[T]) Replace(k string, x T, d time.Duration) error {
 		c.mu.Unlock()
 		return fmt.Errorf("Item %s doesn't exist", k)
 	}
-	c.set(k, x, d)
+	ek, ev, evicted := c.set(k, x, d)
 	c.mu.Unlock()
+	if evicted {
+		c.onEvicted(ek, ev)
+	}
 	return nil
 }
 
+// Get reports the value stored for k, if any, and marks it as the most
+// recently accessed entry for LRU purposes. Caches without a maxItems bound
+// don't need the LRU list reordered, so Get only takes the write lock (and
+// pays the cost of exclusive access) when maxItems is set; otherwise it
+// holds the read lock and stamps Accessed atomically, so concurrent readers
+// keep scaling the way a plain RWMutex-backed cache is expected to.
 func (c *cache[T]) Get(k string) (any, bool) {
-	c.mu.RLock()
-	item, found := c.items[k]
+	if c.maxItems > 0 {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+	} else {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+	}
+	el, found := c.items[k]
 	if !found {
-		c.mu.RUnlock()
 		return nil, false
 	}
-	if item.Expired() {
-		c.mu.RUnlock()
+	en := el.Value.(*entry[T])
+	if en.item.Expired() {
 		return nil, false
 	}
-	c.mu.RUnlock()
-	return item.Object, true
+	atomic.StoreInt64(&en.item.Accessed, time.Now().UnixNano())
+	if c.maxItems > 0 {
+		c.ll.MoveToFront(el)
+	}
+	return en.item.Object, true
 }
 
 func (c *cache[T]) GetWithExpiration(k string) (v T, t time.Time, ok bool) {
-	c.mu.RLock()
-	item, found := c.items[k]
+	if c.maxItems > 0 {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+	} else {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+	}
+	el, found := c.items[k]
 	if !found {
-		c.mu.RUnlock()
 		return v, t, ok
 	}
+	en := el.Value.(*entry[T])
 
-	if item.Expiration > 0 {
-		if time.Now().UnixNano() > item.Expiration {
-			c.mu.RUnlock()
-			return v, t, ok
-		}
-		c.mu.RUnlock()
-		return item.Object, time.Unix(0, item.Expiration), true
+	if en.item.Expiration > 0 && time.Now().UnixNano() > en.item.Expiration {
+		return v, t, ok
 	}
-	c.mu.RUnlock()
-	return item.Object, t, true
+	atomic.StoreInt64(&en.item.Accessed, time.Now().UnixNano())
+	if c.maxItems > 0 {
+		c.ll.MoveToFront(el)
+	}
+	if en.item.Expiration > 0 {
+		return en.item.Object, time.Unix(0, en.item.Expiration), true
+	}
+	return en.item.Object, t, true
+}
+
+// LastAccessed reports the time k was last stored (Set/Add/Replace), hit by
+// Get/GetWithExpiration, or changed by Increment/Decrement. It does not
+// itself count as an access.
+func (c *cache[T]) LastAccessed(k string) (time.Time, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	el, found := c.items[k]
+	if !found {
+		return time.Time{}, false
+	}
+	en := el.Value.(*entry[T])
+	if en.item.Expired() {
+		return time.Time{}, false
+	}
+	return time.Unix(0, atomic.LoadInt64(&en.item.Accessed)), true
 }
 
+// get looks up k without locking or touching its LRU position; it is used
+// internally by Add/Replace, which only need to know whether k exists.
 func (c *cache[T]) get(k string) (v T, ok bool) {
-	item, found := c.items[k]
+	el, found := c.items[k]
 	if !found {
 		return v, ok
 	}
-	if item.Expired() {
+	en := el.Value.(*entry[T])
+	if en.item.Expired() {
 		return v, ok
 	}
-	return item.Object, true
+	return en.item.Object, true
 }
 
 func (c *cache[T]) Increment(k string, n int64) error {
 	c.mu.Lock()
-	v, found := c.items[k]
-	if !found || v.Expired() {
+	el, found := c.items[k]
+	if !found || el.Value.(*entry[T]).item.Expired() {
 		c.mu.Unlock()
 		return fmt.Errorf("Item %s not found", k)
 	}
+	en := el.Value.(*entry[T])
+	v := en.item
 
 	switch vo := any(v.Object).(type) {
 	case int:
@@ -177,18 +257,22 @@ func (c *cache[T]) Increment(k string, n int64) error {
 		c.mu.Unlock()
 		return fmt.Errorf("The value for %s is not an integer", k)
 	}
-	c.items[k] = v
+	v.Accessed = time.Now().UnixNano()
+	en.item = v
+	c.ll.MoveToFront(el)
 	c.mu.Unlock()
 	return nil
 }
 
 func (c *cache[T]) IncrementFloat(k string, n float64) error {
 	c.mu.Lock()
-	v, found := c.items[k]
-	if !found || v.Expired() {
+	el, found := c.items[k]
+	if !found || el.Value.(*entry[T]).item.Expired() {
 		c.mu.Unlock()
 		return fmt.Errorf("Item %s not found", k)
 	}
+	en := el.Value.(*entry[T])
+	v := en.item
 	switch vo := any(v.Object).(type) {
 	case float32:
 		v.Object = any(vo + float32(n)).(T)
@@ -198,18 +282,22 @@ func (c *cache[T]) IncrementFloat(k string, n float64) error {
 		c.mu.Unlock()
 		return fmt.Errorf("The value for %s does not have type float32 or float64", k)
 	}
-	c.items[k] = v
+	v.Accessed = time.Now().UnixNano()
+	en.item = v
+	c.ll.MoveToFront(el)
 	c.mu.Unlock()
 	return nil
 }
 
 func (c *cache[T]) Decrement(k string, n int64) error {
 	c.mu.Lock()
-	v, found := c.items[k]
-	if !found || v.Expired() {
+	el, found := c.items[k]
+	if !found || el.Value.(*entry[T]).item.Expired() {
 		c.mu.Unlock()
 		return fmt.Errorf("Item %s not found", k)
 	}
+	en := el.Value.(*entry[T])
+	v := en.item
 
 	switch vo := any(v.Object).(type) {
 	case int:
@@ -242,18 +330,22 @@ func (c *cache[T]) Decrement(k string, n int64) error {
 		c.mu.Unlock()
 		return fmt.Errorf("The value for %s is not an integer", k)
 	}
-	c.items[k] = v
+	v.Accessed = time.Now().UnixNano()
+	en.item = v
+	c.ll.MoveToFront(el)
 	c.mu.Unlock()
 	return nil
 }
 
 func (c *cache[T]) DecrementFloat(k string, n float64) error {
 	c.mu.Lock()
-	v, found := c.items[k]
-	if !found || v.Expired() {
+	el, found := c.items[k]
+	if !found || el.Value.(*entry[T]).item.Expired() {
 		c.mu.Unlock()
 		return fmt.Errorf("Item %s not found", k)
 	}
+	en := el.Value.(*entry[T])
+	v := en.item
 	switch vo := any(v.Object).(type) {
 	case float32:
 		v.Object = any(vo - float32(n)).(T)
@@ -263,7 +355,9 @@ func (c *cache[T]) DecrementFloat(k string, n float64) error {
 		c.mu.Unlock()
 		return fmt.Errorf("The value for %s does not have type float32 or float64", k)
 	}
-	c.items[k] = v
+	v.Accessed = time.Now().UnixNano()
+	en.item = v
+	c.ll.MoveToFront(el)
 	c.mu.Unlock()
 	return nil
 }
@@ -278,16 +372,49 @@ func (c *cache[T]) Delete(k string) {
 }
 
 func (c *cache[T]) delete(k string) (vo T, ok bool) {
-	if c.onEvicted != nil {
-		if v, found := c.items[k]; found {
-			delete(c.items, k)
-			return v.Object, true
-		}
+	el, found := c.items[k]
+	if !found {
+		return vo, ok
 	}
 	delete(c.items, k)
+	c.ll.Remove(el)
+	if c.onEvicted != nil {
+		return el.Value.(*entry[T]).item.Object, true
+	}
 	return vo, ok
 }
 
+// DeleteLRU evicts up to n of the least-recently-accessed entries, firing
+// onEvicted (if set) for each one once the lock has been released. It is
+// meant for callers that want to relieve memory pressure on demand rather
+// than waiting for maxItems to be exceeded or the janitor's next tick. In a
+// cache without a maxItems bound, Get and GetWithExpiration do not reorder
+// entries, so there DeleteLRU evicts in order of the last
+// Set/Add/Replace/Increment/Decrement of each key, not of the last read.
+func (c *cache[T]) DeleteLRU(n int) {
+	if n <= 0 {
+		return
+	}
+	var evictedItems []keyAndValue[any]
+	c.mu.Lock()
+	for i := 0; i < n; i++ {
+		back := c.ll.Back()
+		if back == nil {
+			break
+		}
+		en := back.Value.(*entry[T])
+		delete(c.items, en.key)
+		c.ll.Remove(back)
+		if c.onEvicted != nil {
+			evictedItems = append(evictedItems, keyAndValue[any]{en.key, en.item.Object})
+		}
+	}
+	c.mu.Unlock()
+	for _, v := range evictedItems {
+		c.onEvicted(v.key, v.value)
+	}
+}
+
 type keyAndValue[T any] struct {
 	key   string
 	value T
@@ -297,14 +424,28 @@ func (c *cache[T]) DeleteExpired() {
 	var evictedItems []keyAndValue[any]
 	now := time.Now().UnixNano()
 	c.mu.Lock()
-	for k, v := range c.items {
-		if v.Expiration > 0 && now > v.Expiration {
+	for k, el := range c.items {
+		if en := el.Value.(*entry[T]); en.item.Expiration > 0 && now > en.item.Expiration {
 			ov, evicted := c.delete(k)
 			if evicted {
 				evictedItems = append(evictedItems, keyAndValue[any]{k, ov})
 			}
 		}
 	}
+	if c.maxItems > 0 {
+		for len(c.items) > c.maxItems {
+			back := c.ll.Back()
+			if back == nil {
+				break
+			}
+			en := back.Value.(*entry[T])
+			delete(c.items, en.key)
+			c.ll.Remove(back)
+			if c.onEvicted != nil {
+				evictedItems = append(evictedItems, keyAndValue[any]{en.key, en.item.Object})
+			}
+		}
+	}
 	c.mu.Unlock()
 	for _, v := range evictedItems {
 		c.onEvicted(v.key, v.value)
@@ -317,19 +458,66 @@ func (c *cache[T]) OnEvicted(f func(string, any)) {
 	c.mu.Unlock()
 }
 
-func newCache[T any](de time.Duration, m map[string]Item[T]) *cache[T] {
+// ItemCount returns the number of items in the cache, including expired
+// items not yet removed by DeleteExpired.
+func (c *cache[T]) ItemCount() int {
+	c.mu.RLock()
+	n := len(c.items)
+	c.mu.RUnlock()
+	return n
+}
+
+func newCache[T any](de time.Duration, maxItems int, m map[string]Item[T]) *cache[T] {
 	if de == DefaultExpiration {
 		de = NoExpiration
 	}
 	c := &cache[T]{
 		defaultExpiration: de,
-		items:             m,
+		maxItems:          maxItems,
+		items:             make(map[string]*list.Element, len(m)),
+		ll:                list.New(),
+	}
+	for k, v := range m {
+		c.items[k] = c.ll.PushFront(&entry[T]{key: k, item: v})
 	}
 	return c
 }
 
-func newCacheWithJanitor[T any](de time.Duration, ci time.Duration, m map[string]Item[T]) *Cache[T] {
-	c := newCache(de, m)
+// janitor periodically calls DeleteExpired on the cache it is attached to,
+// until it is told to stop.
+type janitor[T any] struct {
+	interval time.Duration
+	stop     chan bool
+}
+
+func (j *janitor[T]) Run(c *cache[T]) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.DeleteExpired()
+		case <-j.stop:
+			return
+		}
+	}
+}
+
+func stopJanitor[T any](c *Cache[T]) {
+	c.janitor.stop <- true
+}
+
+func runJanitor[T any](c *cache[T], ci time.Duration) {
+	j := &janitor[T]{
+		interval: ci,
+		stop:     make(chan bool),
+	}
+	c.janitor = j
+	go j.Run(c)
+}
+
+func newCacheWithJanitor[T any](de time.Duration, ci time.Duration, maxItems int, m map[string]Item[T]) *Cache[T] {
+	c := newCache(de, maxItems, m)
 	// This trick ensures that the janitor goroutine (which--granted it
 	// was enabled--is running DeleteExpired on c forever) does not keep
 	// the returned C object from being garbage collected. When it is
@@ -337,12 +525,39 @@ func newCacheWithJanitor[T any](de time.Duration, ci time.Duration, m map[string
 	// which c can be collected.
 	C := &Cache[T]{c}
 	if ci > 0 {
-		// pass
+		runJanitor(c, ci)
+		runtime.SetFinalizer(C, stopJanitor[T])
 	}
 	return C
 }
 
+// Stop halts the janitor goroutine, if one was started, and removes the
+// finalizer so it isn't run again when C is garbage collected. Use this to
+// release a cache's resources immediately instead of waiting on GC. It is
+// safe to call more than once.
+func (c *Cache[T]) Stop() {
+	c.mu.Lock()
+	j := c.janitor
+	c.janitor = nil
+	c.mu.Unlock()
+	if j == nil {
+		return
+	}
+	runtime.SetFinalizer(c, nil)
+	j.stop <- true
+}
+
 func New[T any](defaultExpiration, cleanupInterval time.Duration) *Cache[T] {
 	items := make(map[string]Item[T])
-	return newCacheWithJanitor(defaultExpiration, cleanupInterval, items)
+	return newCacheWithJanitor(defaultExpiration, cleanupInterval, 0, items)
+}
+
+// NewWithLRU is like New but also bounds the cache to at most maxItems
+// entries. Once the bound is reached, Set/Add evict the
+// least-recently-accessed entry (tracked via Set/Add/Replace,
+// Get/GetWithExpiration hits and Increment/Decrement) to make room for the
+// new one, firing onEvicted for it like any other eviction.
+func NewWithLRU[T any](defaultExpiration, cleanupInterval time.Duration, maxItems int) *Cache[T] {
+	items := make(map[string]Item[T])
+	return newCacheWithJanitor(defaultExpiration, cleanupInterval, maxItems, items)
 }
@@ -1,6 +1,10 @@
 package cache
 
-import "testing"
+import (
+	"sync"
+	"testing"
+	"time"
+)
 
 func TestCache(t *testing.T) {
 	tc := New[any](DefaultExpiration, 0)
@@ -37,3 +41,180 @@ func TestIncrementWithInt(t *testing.T) {
 		t.Error("tint is not 3:", x)
 	}
 }
+
+func TestJanitor(t *testing.T) {
+	tc := New[any](DefaultExpiration, 10*time.Millisecond)
+	defer tc.Stop()
+
+	tc.Set("foo", "bar", 20*time.Millisecond)
+
+	_, found := tc.Get("foo")
+	if !found {
+		t.Error("foo was not found right after being set")
+	}
+
+	<-time.After(100 * time.Millisecond)
+
+	_, found = tc.Get("foo")
+	if found {
+		t.Error("foo was found, but should have been evicted by the janitor")
+	}
+	// Get hides expired items on its own; ItemCount still counts them until
+	// they are removed, so it shows whether the janitor actually ran.
+	if n := tc.ItemCount(); n != 0 {
+		t.Errorf("expected the janitor to have removed foo, but %d items remain", n)
+	}
+}
+
+func TestStopJanitor(t *testing.T) {
+	tc := New[any](DefaultExpiration, 10*time.Millisecond)
+	tc.Set("foo", "bar", DefaultExpiration)
+	tc.Stop()
+
+	// Stopping twice, or on a cache with no janitor, must not panic or block.
+	tc.Stop()
+
+	tc2 := New[any](DefaultExpiration, 0)
+	tc2.Stop()
+}
+
+func TestLRUEviction(t *testing.T) {
+	tc := NewWithLRU[any](DefaultExpiration, 0, 2)
+	defer tc.Stop()
+
+	var evicted []string
+	tc.OnEvicted(func(k string, v any) {
+		evicted = append(evicted, k)
+	})
+
+	tc.Set("a", 1, DefaultExpiration)
+	tc.Set("b", 2, DefaultExpiration)
+
+	// Touch "a" so "b" becomes the least recently accessed.
+	if _, found := tc.Get("a"); !found {
+		t.Error("a was not found")
+	}
+
+	tc.Set("c", 3, DefaultExpiration)
+
+	if len(evicted) != 1 || evicted[0] != "b" {
+		t.Errorf("expected b to be evicted, got %v", evicted)
+	}
+	if _, found := tc.Get("b"); found {
+		t.Error("b should have been evicted")
+	}
+	if _, found := tc.Get("a"); !found {
+		t.Error("a should still be present")
+	}
+	if _, found := tc.Get("c"); !found {
+		t.Error("c should still be present")
+	}
+}
+
+func TestLRUEvictionTouchedByIncrement(t *testing.T) {
+	tc := NewWithLRU[any](DefaultExpiration, 0, 2)
+	defer tc.Stop()
+
+	var evicted []string
+	tc.OnEvicted(func(k string, v any) {
+		evicted = append(evicted, k)
+	})
+
+	tc.Set("a", 1, DefaultExpiration)
+	tc.Set("b", 2, DefaultExpiration)
+
+	// Touch "a" via Increment, not Get, so "b" becomes the least recently
+	// accessed even though it's never read directly.
+	if err := tc.Increment("a", 1); err != nil {
+		t.Fatal("Increment failed:", err)
+	}
+
+	tc.Set("c", 3, DefaultExpiration)
+
+	if len(evicted) != 1 || evicted[0] != "b" {
+		t.Errorf("expected b to be evicted, got %v", evicted)
+	}
+	if _, found := tc.Get("a"); !found {
+		t.Error("a should still be present; Increment should count as an access")
+	}
+}
+
+func TestLRUEvictionConcurrent(t *testing.T) {
+	tc := NewWithLRU[any](DefaultExpiration, 0, 10)
+	defer tc.Stop()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			k := string(rune('a' + i%26))
+			tc.Set(k, i, DefaultExpiration)
+			tc.Get(k)
+		}(i)
+	}
+	wg.Wait()
+
+	if n := tc.ItemCount(); n > 10 {
+		t.Errorf("cache grew past maxItems: %d items", n)
+	}
+}
+
+func TestDeleteLRU(t *testing.T) {
+	tc := New[any](DefaultExpiration, 0)
+
+	var evicted []string
+	tc.OnEvicted(func(k string, v any) {
+		evicted = append(evicted, k)
+	})
+
+	tc.Set("a", 1, DefaultExpiration)
+	tc.Set("b", 2, DefaultExpiration)
+	tc.Get("b") // b is now most recently accessed, a is least
+
+	tc.DeleteLRU(1)
+
+	if len(evicted) != 1 || evicted[0] != "a" {
+		t.Errorf("expected a to be evicted, got %v", evicted)
+	}
+	if _, found := tc.Get("b"); !found {
+		t.Error("b should still be present")
+	}
+}
+
+func TestLastAccessed(t *testing.T) {
+	tc := New[any](DefaultExpiration, 0)
+	tc.Set("a", 1, DefaultExpiration)
+
+	before := time.Now()
+	if _, found := tc.Get("a"); !found {
+		t.Error("a was not found")
+	}
+	accessed, found := tc.LastAccessed("a")
+	if !found {
+		t.Error("LastAccessed did not find a")
+	}
+	if accessed.Before(before) {
+		t.Error("LastAccessed returned a time before the Get that should have set it")
+	}
+
+	if _, found := tc.LastAccessed("missing"); found {
+		t.Error("LastAccessed found a key that was never set")
+	}
+}
+
+// BenchmarkCacheGetReadOnly measures Get on a plain (non-LRU) cache under
+// concurrent readers. Without a maxItems bound, Get should only take the
+// read lock, so this should scale with GOMAXPROCS rather than degrade.
+func BenchmarkCacheGetReadOnly(b *testing.B) {
+	tc := New[any](DefaultExpiration, 0)
+	defer tc.Stop()
+	tc.Set("k", "v", DefaultExpiration)
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			tc.Get("k")
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+)
+
+// Number is the set of types NumericCache accepts. It plays the role of
+// golang.org/x/exp/constraints.Integer | constraints.Float without pulling
+// in that dependency.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// NumericCache is a Cache[T] restricted to a numeric T. Because T is
+// constrained, Increment/Decrement do plain arithmetic on T instead of the
+// any-boxing, 13-arm type switch Cache[T].Increment/Decrement need to
+// support an arbitrary T, which matters in increment-heavy workloads such
+// as counters and rate limiters.
+type NumericCache[T Number] struct {
+	*Cache[T]
+}
+
+// NewNumeric is like New but returns a NumericCache, whose Increment and
+// Decrement are type-checked at compile time instead of returning a runtime
+// error for a non-numeric T.
+func NewNumeric[T Number](defaultExpiration, cleanupInterval time.Duration) *NumericCache[T] {
+	return &NumericCache[T]{New[T](defaultExpiration, cleanupInterval)}
+}
+
+func (nc *NumericCache[T]) Increment(k string, n T) error {
+	_, err := nc.IncrementAndGet(k, n)
+	return err
+}
+
+func (nc *NumericCache[T]) Decrement(k string, n T) error {
+	_, err := nc.IncrementAndGet(k, -n)
+	return err
+}
+
+// IncrementAndGet is like Increment but also returns the item's new value.
+func (nc *NumericCache[T]) IncrementAndGet(k string, n T) (T, error) {
+	nc.mu.Lock()
+	defer nc.mu.Unlock()
+	el, found := nc.items[k]
+	if !found || el.Value.(*entry[T]).item.Expired() {
+		var zero T
+		return zero, fmt.Errorf("Item %s not found", k)
+	}
+	en := el.Value.(*entry[T])
+	en.item.Object += n
+	return en.item.Object, nil
+}
+
+// GetOrSet returns the current value for k if it is present and unexpired,
+// otherwise it stores v with expiration d and returns v.
+func (nc *NumericCache[T]) GetOrSet(k string, v T, d time.Duration) T {
+	nc.mu.Lock()
+	if el, found := nc.items[k]; found {
+		en := el.Value.(*entry[T])
+		if !en.item.Expired() {
+			val := en.item.Object
+			nc.mu.Unlock()
+			return val
+		}
+	}
+	ek, ev, evicted := nc.set(k, v, d)
+	nc.mu.Unlock()
+	if evicted {
+		nc.onEvicted(ek, ev)
+	}
+	return v
+}
@@ -0,0 +1,61 @@
+package cache
+
+import "testing"
+
+func TestNumericCacheIncrementDecrement(t *testing.T) {
+	nc := NewNumeric[int](DefaultExpiration, 0)
+	defer nc.Stop()
+
+	nc.Set("n", 10, DefaultExpiration)
+
+	if err := nc.Increment("n", 5); err != nil {
+		t.Fatal("Increment failed:", err)
+	}
+	v, found := nc.Get("n")
+	if !found || v.(int) != 15 {
+		t.Error("n is not 15:", v)
+	}
+
+	if err := nc.Decrement("n", 3); err != nil {
+		t.Fatal("Decrement failed:", err)
+	}
+	v, found = nc.Get("n")
+	if !found || v.(int) != 12 {
+		t.Error("n is not 12:", v)
+	}
+
+	if err := nc.Increment("missing", 1); err == nil {
+		t.Error("expected an error incrementing a missing key")
+	}
+}
+
+func TestNumericCacheIncrementAndGet(t *testing.T) {
+	nc := NewNumeric[float64](DefaultExpiration, 0)
+	defer nc.Stop()
+
+	nc.Set("f", 1.5, DefaultExpiration)
+	got, err := nc.IncrementAndGet("f", 0.5)
+	if err != nil {
+		t.Fatal("IncrementAndGet failed:", err)
+	}
+	if got != 2.0 {
+		t.Error("expected 2.0, got", got)
+	}
+}
+
+func TestNumericCacheGetOrSet(t *testing.T) {
+	nc := NewNumeric[int](DefaultExpiration, 0)
+	defer nc.Stop()
+
+	v := nc.GetOrSet("counter", 1, DefaultExpiration)
+	if v != 1 {
+		t.Error("expected 1 on first GetOrSet, got", v)
+	}
+
+	nc.Increment("counter", 4)
+
+	v = nc.GetOrSet("counter", 100, DefaultExpiration)
+	if v != 5 {
+		t.Error("expected GetOrSet to return the existing value 5, got", v)
+	}
+}
@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Items returns a snapshot of the cache's contents, including items that
+// have expired but have not yet been removed by DeleteExpired.
+func (c *cache[T]) Items() map[string]Item[T] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	m := make(map[string]Item[T], len(c.items))
+	for k, el := range c.items {
+		m[k] = el.Value.(*entry[T]).item
+	}
+	return m
+}
+
+// Save writes the cache's contents to w as gob-encoded data, so it can
+// later be restored with Load or LoadFile. If T is an interface type (such
+// as any), the concrete type of every stored value is registered with the
+// gob package first; values whose type contains unexported fields or funcs
+// cannot be gob-encoded and will make Save fail.
+func (c *cache[T]) Save(w io.Writer) (err error) {
+	defer func() {
+		if x := recover(); x != nil {
+			err = fmt.Errorf("Error registering item types with Gob library")
+		}
+	}()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	items := make(map[string]Item[T], len(c.items))
+	for k, el := range c.items {
+		item := el.Value.(*entry[T]).item
+		gob.Register(item.Object)
+		items[k] = item
+	}
+	return gob.NewEncoder(w).Encode(&items)
+}
+
+// SaveFile saves the cache's contents to the given filename, writing it
+// atomically via a temporary file in the same directory followed by a
+// rename, so a crash mid-write can't leave a truncated file at path.
+func (c *cache[T]) SaveFile(path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+
+	err = c.Save(tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	return os.Rename(tmpName, path)
+}
+
+// Load adds the gob-encoded items read from r to the cache, overwriting
+// existing items with the same keys. Already-expired items are skipped. If
+// maxItems is set and loading pushes the cache over the bound, the
+// least-recently-used items are evicted to make room, firing onEvicted (if
+// set) for each one once the lock has been released, the same as Set and
+// DeleteExpired do.
+func (c *cache[T]) Load(r io.Reader) error {
+	items := map[string]Item[T]{}
+	if err := gob.NewDecoder(r).Decode(&items); err != nil {
+		return err
+	}
+
+	now := time.Now().UnixNano()
+	var evictedItems []keyAndValue[any]
+	c.mu.Lock()
+	for k, item := range items {
+		if item.Expiration > 0 && now > item.Expiration {
+			continue
+		}
+		if el, found := c.items[k]; found {
+			el.Value.(*entry[T]).item = item
+			c.ll.MoveToFront(el)
+			continue
+		}
+		c.items[k] = c.ll.PushFront(&entry[T]{key: k, item: item})
+	}
+	if c.maxItems > 0 {
+		for len(c.items) > c.maxItems {
+			back := c.ll.Back()
+			if back == nil {
+				break
+			}
+			en := back.Value.(*entry[T])
+			delete(c.items, en.key)
+			c.ll.Remove(back)
+			if c.onEvicted != nil {
+				evictedItems = append(evictedItems, keyAndValue[any]{en.key, en.item.Object})
+			}
+		}
+	}
+	c.mu.Unlock()
+	for _, v := range evictedItems {
+		c.onEvicted(v.key, v.value)
+	}
+	return nil
+}
+
+// LoadFile is like Load but reads the gob-encoded items from the named
+// file, as written by SaveFile.
+func (c *cache[T]) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return c.Load(f)
+}
+
+// NewFrom is like New but seeds the cache with items, typically a snapshot
+// taken from another cache with Items.
+func NewFrom[T any](defaultExpiration, cleanupInterval time.Duration, items map[string]Item[T]) *Cache[T] {
+	return newCacheWithJanitor(defaultExpiration, cleanupInterval, 0, items)
+}
+
+// Register forwards to gob.Register. Callers with a Cache[any] (or any
+// other cache of an interface type) that holds more than one concrete type
+// must register each concrete type before Load/LoadFile can decode it.
+func Register(value any) {
+	gob.Register(value)
+}
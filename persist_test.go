@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestSaveLoad(t *testing.T) {
+	tc := New[any](DefaultExpiration, 0)
+	tc.Set("a", 1, DefaultExpiration)
+	tc.Set("b", "bar", 50*time.Millisecond)
+
+	var buf bytes.Buffer
+	if err := tc.Save(&buf); err != nil {
+		t.Fatal("Save failed:", err)
+	}
+
+	tc2 := New[any](DefaultExpiration, 0)
+	if err := tc2.Load(&buf); err != nil {
+		t.Fatal("Load failed:", err)
+	}
+
+	a, found := tc2.Get("a")
+	if !found || a.(int) != 1 {
+		t.Error("a did not survive the round trip:", a)
+	}
+	b, bTime, found := tc2.GetWithExpiration("b")
+	if !found || b.(string) != "bar" {
+		t.Error("b did not survive the round trip:", b)
+	}
+	if bTime.IsZero() {
+		t.Error("b's expiration did not survive the round trip")
+	}
+}
+
+func TestSaveLoadSkipsExpired(t *testing.T) {
+	tc := New[any](DefaultExpiration, 0)
+	tc.Set("gone", 1, 10*time.Millisecond)
+	<-time.After(20 * time.Millisecond)
+
+	var buf bytes.Buffer
+	if err := tc.Save(&buf); err != nil {
+		t.Fatal("Save failed:", err)
+	}
+
+	tc2 := New[any](DefaultExpiration, 0)
+	if err := tc2.Load(&buf); err != nil {
+		t.Fatal("Load failed:", err)
+	}
+	if _, found := tc2.Get("gone"); found {
+		t.Error("an already-expired item was loaded into the cache")
+	}
+	if n := tc2.ItemCount(); n != 0 {
+		t.Errorf("an already-expired item was loaded into the cache: %d items", n)
+	}
+}
+
+func TestLoadFiresOnEvicted(t *testing.T) {
+	src := New[any](DefaultExpiration, 0)
+	src.Set("a", 1, DefaultExpiration)
+	src.Set("b", 2, DefaultExpiration)
+
+	var buf bytes.Buffer
+	if err := src.Save(&buf); err != nil {
+		t.Fatal("Save failed:", err)
+	}
+
+	tc := NewWithLRU[any](DefaultExpiration, 0, 1)
+	tc.Set("old", 0, DefaultExpiration)
+
+	var evicted []string
+	tc.OnEvicted(func(k string, _ any) { evicted = append(evicted, k) })
+
+	if err := tc.Load(&buf); err != nil {
+		t.Fatal("Load failed:", err)
+	}
+
+	if len(evicted) == 0 {
+		t.Error("Load exceeded maxItems but onEvicted was never called")
+	}
+	if n := tc.ItemCount(); n != 1 {
+		t.Errorf("expected 1 item after Load enforced maxItems, got %d", n)
+	}
+}
+
+func TestSaveFileLoadFile(t *testing.T) {
+	tc := New[any](DefaultExpiration, 0)
+	tc.Set("a", 1, DefaultExpiration)
+
+	path := t.TempDir() + "/cache.gob"
+	if err := tc.SaveFile(path); err != nil {
+		t.Fatal("SaveFile failed:", err)
+	}
+
+	tc2 := New[any](DefaultExpiration, 0)
+	if err := tc2.LoadFile(path); err != nil {
+		t.Fatal("LoadFile failed:", err)
+	}
+	a, found := tc2.Get("a")
+	if !found || a.(int) != 1 {
+		t.Error("a did not survive the file round trip:", a)
+	}
+}
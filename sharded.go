@@ -0,0 +1,200 @@
+package cache
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// ShardedCache fans a key space out across a fixed number of independent
+// caches ("shards"), each with its own lock, so that concurrent callers
+// touching different keys don't contend on a single mutex the way they
+// would with Cache.
+type ShardedCache[T any] struct {
+	*shardedCache[T]
+}
+
+type shardedCache[T any] struct {
+	seed    uint32
+	mask    uint32
+	cs      []*cache[T]
+	mu      sync.Mutex
+	janitor *shardedJanitor[T]
+}
+
+// djb33 is djb2 with the addition of a per-cache seed, so that two
+// ShardedCache instances don't funnel the same adversarial keys into the
+// same shard.
+func djb33(seed uint32, k string) uint32 {
+	var (
+		l = uint32(len(k))
+		d = 5381 + seed + l
+		i = uint32(0)
+	)
+	if l >= 4 {
+		for i+4 <= l {
+			d = (d * 33) ^ uint32(k[i])
+			d = (d * 33) ^ uint32(k[i+1])
+			d = (d * 33) ^ uint32(k[i+2])
+			d = (d * 33) ^ uint32(k[i+3])
+			i += 4
+		}
+	}
+	switch l - i {
+	case 3:
+		d = (d * 33) ^ uint32(k[i+2])
+		fallthrough
+	case 2:
+		d = (d * 33) ^ uint32(k[i+1])
+		fallthrough
+	case 1:
+		d = (d * 33) ^ uint32(k[i])
+	}
+	return d ^ (d >> 15)
+}
+
+func (sc *shardedCache[T]) bucket(k string) *cache[T] {
+	return sc.cs[djb33(sc.seed, k)&sc.mask]
+}
+
+func (sc *shardedCache[T]) Get(k string) (any, bool) {
+	return sc.bucket(k).Get(k)
+}
+
+func (sc *shardedCache[T]) GetWithExpiration(k string) (T, time.Time, bool) {
+	return sc.bucket(k).GetWithExpiration(k)
+}
+
+func (sc *shardedCache[T]) Set(k string, x T, d time.Duration) {
+	sc.bucket(k).Set(k, x, d)
+}
+
+func (sc *shardedCache[T]) SetDefault(k string, x T) {
+	sc.bucket(k).SetDefault(k, x)
+}
+
+func (sc *shardedCache[T]) Add(k string, x T, d time.Duration) error {
+	return sc.bucket(k).Add(k, x, d)
+}
+
+func (sc *shardedCache[T]) Replace(k string, x T, d time.Duration) error {
+	return sc.bucket(k).Replace(k, x, d)
+}
+
+func (sc *shardedCache[T]) Delete(k string) {
+	sc.bucket(k).Delete(k)
+}
+
+func (sc *shardedCache[T]) Increment(k string, n int64) error {
+	return sc.bucket(k).Increment(k, n)
+}
+
+func (sc *shardedCache[T]) Decrement(k string, n int64) error {
+	return sc.bucket(k).Decrement(k, n)
+}
+
+// OnEvicted registers f on every shard; it is called with the shard's lock
+// released, same as Cache.OnEvicted.
+func (sc *shardedCache[T]) OnEvicted(f func(string, any)) {
+	for _, c := range sc.cs {
+		c.OnEvicted(f)
+	}
+}
+
+// DeleteExpired walks every shard removing expired items. It is normally
+// driven by the sharded cache's own janitor rather than called directly.
+func (sc *shardedCache[T]) DeleteExpired() {
+	for _, c := range sc.cs {
+		c.DeleteExpired()
+	}
+}
+
+// ItemCount returns the total number of items across all shards.
+func (sc *shardedCache[T]) ItemCount() int {
+	n := 0
+	for _, c := range sc.cs {
+		n += c.ItemCount()
+	}
+	return n
+}
+
+// shardedJanitor drives DeleteExpired for every shard from a single
+// goroutine, rather than running one janitor per shard.
+type shardedJanitor[T any] struct {
+	interval time.Duration
+	stop     chan bool
+}
+
+func (j *shardedJanitor[T]) Run(sc *shardedCache[T]) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			sc.DeleteExpired()
+		case <-j.stop:
+			return
+		}
+	}
+}
+
+func runShardedJanitor[T any](sc *shardedCache[T], ci time.Duration) {
+	j := &shardedJanitor[T]{
+		interval: ci,
+		stop:     make(chan bool),
+	}
+	sc.janitor = j
+	go j.Run(sc)
+}
+
+// Stop halts the janitor goroutine, if one was started. It is safe to call
+// more than once.
+func (sc *ShardedCache[T]) Stop() {
+	sc.mu.Lock()
+	j := sc.janitor
+	sc.janitor = nil
+	sc.mu.Unlock()
+	if j == nil {
+		return
+	}
+	runtime.SetFinalizer(sc, nil)
+	j.stop <- true
+}
+
+// nextPowerOfTwo rounds n up to the next power of two so shard selection
+// can use a bitmask instead of a modulo.
+func nextPowerOfTwo(n int) int {
+	if n <= 1 {
+		return 1
+	}
+	n--
+	n |= n >> 1
+	n |= n >> 2
+	n |= n >> 4
+	n |= n >> 8
+	n |= n >> 16
+	return n + 1
+}
+
+// NewSharded returns a ShardedCache with shards (rounded up to the next
+// power of two) independent caches, each behaving like a Cache created with
+// defaultExpiration. A single janitor goroutine sweeps every shard for
+// expired items every cleanupInterval.
+func NewSharded[T any](defaultExpiration, cleanupInterval time.Duration, shards int) *ShardedCache[T] {
+	n := nextPowerOfTwo(shards)
+	sc := &shardedCache[T]{
+		seed: rand.Uint32(),
+		mask: uint32(n - 1),
+		cs:   make([]*cache[T], n),
+	}
+	for i := 0; i < n; i++ {
+		sc.cs[i] = newCache(defaultExpiration, 0, make(map[string]Item[T]))
+	}
+	SC := &ShardedCache[T]{sc}
+	if cleanupInterval > 0 {
+		runShardedJanitor(sc, cleanupInterval)
+		runtime.SetFinalizer(SC, func(sc *ShardedCache[T]) { sc.Stop() })
+	}
+	return SC
+}
@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShardedCache(t *testing.T) {
+	tc := NewSharded[any](DefaultExpiration, 0, 13)
+	defer tc.Stop()
+
+	tc.Set("a", 1, DefaultExpiration)
+	tc.Set("b", "bar", DefaultExpiration)
+
+	x, found := tc.Get("a")
+	if !found || x.(int) != 1 {
+		t.Error("a was not found, or did not have the expected value:", x)
+	}
+	x, found = tc.Get("b")
+	if !found || x.(string) != "bar" {
+		t.Error("b was not found, or did not have the expected value:", x)
+	}
+	if _, found := tc.Get("c"); found {
+		t.Error("c was found, but was never set")
+	}
+
+	if n := tc.ItemCount(); n != 2 {
+		t.Errorf("expected 2 items across all shards, got %d", n)
+	}
+
+	tc.Delete("a")
+	if _, found := tc.Get("a"); found {
+		t.Error("a was found after being deleted")
+	}
+	if n := tc.ItemCount(); n != 1 {
+		t.Errorf("expected 1 item after deleting a, got %d", n)
+	}
+}
+
+func TestShardedCacheJanitor(t *testing.T) {
+	tc := NewSharded[any](DefaultExpiration, 10*time.Millisecond, 4)
+	defer tc.Stop()
+
+	tc.Set("foo", "bar", 20*time.Millisecond)
+	<-time.After(100 * time.Millisecond)
+
+	if _, found := tc.Get("foo"); found {
+		t.Error("foo was found, but should have been evicted by the janitor")
+	}
+	if n := tc.ItemCount(); n != 0 {
+		t.Errorf("expected the janitor to have removed foo, but %d items remain", n)
+	}
+}
+
+func TestShardedCacheConcurrent(t *testing.T) {
+	tc := NewSharded[any](DefaultExpiration, 0, 16)
+	defer tc.Stop()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			k := strconv.Itoa(i % 50)
+			tc.Set(k, i, DefaultExpiration)
+			tc.Get(k)
+		}(i)
+	}
+	wg.Wait()
+
+	if n := tc.ItemCount(); n != 50 {
+		t.Errorf("expected 50 items, got %d", n)
+	}
+}
+
+func TestDjb33Distribution(t *testing.T) {
+	const (
+		nKeys   = 10000
+		nBucket = 16
+	)
+	counts := make([]int, nBucket)
+	for i := 0; i < nKeys; i++ {
+		k := fmt.Sprintf("%032x", i)
+		counts[djb33(0, k)%nBucket]++
+	}
+
+	expected := nKeys / nBucket
+	for b, n := range counts {
+		if n > 2*expected {
+			t.Errorf("bucket %d got %d of %d keys, more than 2x the expected %d; djb33 is not folding in all key bytes", b, n, nKeys, expected)
+		}
+	}
+}
+
+func TestDjb33FixedWidthKeysDiffer(t *testing.T) {
+	if djb33(0, "aaaa") == djb33(0, "bbbb") {
+		t.Error("djb33 produced the same hash for distinct same-length keys")
+	}
+}
+
+func benchmarkCacheGetSet(b *testing.B, get func(k string) (any, bool), set func(k string, v any)) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			k := strconv.Itoa(i % 1000)
+			set(k, i)
+			get(k)
+			i++
+		}
+	})
+}
+
+func BenchmarkCacheGetSetConcurrent(b *testing.B) {
+	tc := New[any](DefaultExpiration, 0)
+	defer tc.Stop()
+	benchmarkCacheGetSet(b, tc.Get, func(k string, v any) { tc.Set(k, v, DefaultExpiration) })
+}
+
+func BenchmarkShardedCacheGetSetConcurrent(b *testing.B) {
+	tc := NewSharded[any](DefaultExpiration, 0, 256)
+	defer tc.Stop()
+	benchmarkCacheGetSet(b, tc.Get, func(k string, v any) { tc.Set(k, v, DefaultExpiration) })
+}
@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// inflight tracks a loader call that is in progress for a given key, so
+// concurrent GetOrLoad/GetOrLoadContext calls for that key can share its
+// result instead of each calling loader themselves.
+type inflight[T any] struct {
+	wg  sync.WaitGroup
+	val T
+	err error
+}
+
+// GetOrLoad returns the cached value for k if present and unexpired.
+// Otherwise it calls loader, stores the result with expiration d if loader
+// succeeds, and returns it. Concurrent GetOrLoad calls for the same absent
+// k coalesce onto a single loader call; the rest block and share its
+// result, so a cold key behind an expensive loader (a DB query, an HTTP
+// call) is only fetched once no matter how many callers ask for it at
+// once.
+func (c *cache[T]) GetOrLoad(k string, d time.Duration, loader func() (T, error)) (T, error) {
+	if v, found := c.Get(k); found {
+		return v.(T), nil
+	}
+
+	f, winner := c.startInflight(k)
+	if !winner {
+		f.wg.Wait()
+		return f.val, f.err
+	}
+
+	// A load that finished between the Get above and startInflight has
+	// already filled the cache; use that instead of calling loader again.
+	if v, found := c.Get(k); found {
+		f.val = v.(T)
+	} else if f.val, f.err = loader(); f.err == nil {
+		c.Set(k, f.val, d)
+	}
+	c.finishInflight(k, f)
+	return f.val, f.err
+}
+
+// GetOrLoadContext is like GetOrLoad, but a waiter gives up and returns
+// ctx.Err() if ctx is done before the in-flight loader call finishes. The
+// loader call itself is not canceled by ctx: it keeps running in the
+// background so the cache still gets filled for the next caller.
+func (c *cache[T]) GetOrLoadContext(ctx context.Context, k string, d time.Duration, loader func() (T, error)) (T, error) {
+	if v, found := c.Get(k); found {
+		return v.(T), nil
+	}
+
+	f, winner := c.startInflight(k)
+	if winner {
+		go func() {
+			if v, found := c.Get(k); found {
+				f.val = v.(T)
+			} else if f.val, f.err = loader(); f.err == nil {
+				c.Set(k, f.val, d)
+			}
+			c.finishInflight(k, f)
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		f.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return f.val, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// startInflight registers the caller as the loader for k if no load is
+// already in progress, returning (that inflight, true), or joins an
+// existing one, returning (it, false).
+func (c *cache[T]) startInflight(k string) (f *inflight[T], winner bool) {
+	c.inflightMu.Lock()
+	defer c.inflightMu.Unlock()
+	if c.inflight == nil {
+		c.inflight = make(map[string]*inflight[T])
+	}
+	if f, found := c.inflight[k]; found {
+		return f, false
+	}
+	f = &inflight[T]{}
+	f.wg.Add(1)
+	c.inflight[k] = f
+	return f, true
+}
+
+func (c *cache[T]) finishInflight(k string, f *inflight[T]) {
+	c.inflightMu.Lock()
+	delete(c.inflight, k)
+	c.inflightMu.Unlock()
+	f.wg.Done()
+}
@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetOrLoad(t *testing.T) {
+	tc := New[any](DefaultExpiration, 0)
+	defer tc.Stop()
+
+	var calls int32
+	loader := func() (any, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(20 * time.Millisecond)
+		return "value", nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]any, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			v, err := tc.GetOrLoad("k", DefaultExpiration, loader)
+			if err != nil {
+				t.Error("unexpected error:", err)
+			}
+			results[i] = v
+		}(i)
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("loader was called %d times, want 1", got)
+	}
+	for i, v := range results {
+		if v != "value" {
+			t.Errorf("result %d = %v, want \"value\"", i, v)
+		}
+	}
+
+	v, found := tc.Get("k")
+	if !found || v != "value" {
+		t.Error("GetOrLoad did not populate the cache")
+	}
+}
+
+func TestGetOrLoadCached(t *testing.T) {
+	tc := New[any](DefaultExpiration, 0)
+	defer tc.Stop()
+
+	tc.Set("k", "already here", DefaultExpiration)
+
+	called := false
+	v, err := tc.GetOrLoad("k", DefaultExpiration, func() (any, error) {
+		called = true
+		return "loaded", nil
+	})
+	if err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if called {
+		t.Error("loader was called for a key that was already cached")
+	}
+	if v != "already here" {
+		t.Error("expected the cached value, got", v)
+	}
+}
+
+func TestGetOrLoadContextCancel(t *testing.T) {
+	tc := New[any](DefaultExpiration, 0)
+	defer tc.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := tc.GetOrLoadContext(ctx, "k", DefaultExpiration, func() (any, error) {
+		time.Sleep(100 * time.Millisecond)
+		return "value", nil
+	})
+	if err == nil {
+		t.Fatal("expected a context deadline error")
+	}
+
+	// The loader keeps running in the background and should still fill the
+	// cache once it completes.
+	<-time.After(150 * time.Millisecond)
+	v, found := tc.Get("k")
+	if !found || v != "value" {
+		t.Error("background loader did not fill the cache after the waiter gave up")
+	}
+}